@@ -0,0 +1,22 @@
+package retry
+
+import "context"
+
+// Do runs f one or more times via tr.Try,
+// using tr's policy for retrying, backoff, and error handling,
+// and returns the value f produced on success.
+//
+// This spares callers the boilerplate of closing over a result variable
+// when retrying a function that produces a value,
+// such as an RPC or HTTP call.
+func Do[T any](ctx context.Context, tr Tryer, f func(int) (T, error)) (T, error) {
+	var result T
+
+	err := tr.Try(ctx, func(n int) error {
+		var err error
+		result, err = f(n)
+		return err
+	})
+
+	return result, err
+}
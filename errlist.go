@@ -0,0 +1,73 @@
+package retry
+
+import "fmt"
+
+// errList accumulates the errors seen across the attempts of a single [Tryer.Try] call,
+// optionally capping the memory it uses by keeping only the first and last portions of the list.
+type errList struct {
+	// max is the cap on the number of errors retained (see [Tryer.MaxErrors]).
+	// A value of 0 or less means no cap.
+	max int
+
+	head  []error // the first len(head) errors seen
+	tail  []error // a ring buffer of the most recent errors, once head is full
+	pos   int     // the next slot in tail to overwrite
+	total int     // the total number of errors seen, including any dropped from tail
+}
+
+// add records a non-nil error returned by f.
+func (l *errList) add(err error) {
+	l.total++
+
+	if l.max <= 0 {
+		l.head = append(l.head, err)
+		return
+	}
+
+	headCap := (l.max + 1) / 2
+	tailCap := l.max - headCap
+	if tailCap <= 0 {
+		// max is too small to split; keep headCap+tailCap == max
+		// but always leave room for at least one tail slot,
+		// so a drop is never silent (see errs).
+		tailCap = 1
+		if headCap > 0 {
+			headCap--
+		}
+	}
+
+	if len(l.head) < headCap {
+		l.head = append(l.head, err)
+		return
+	}
+
+	if l.tail == nil {
+		l.tail = make([]error, 0, tailCap)
+	}
+	if len(l.tail) < tailCap {
+		l.tail = append(l.tail, err)
+		return
+	}
+	l.tail[l.pos] = err
+	l.pos = (l.pos + 1) % tailCap
+}
+
+// errs returns the accumulated errors in the order they were seen,
+// with a placeholder error in place of any that were dropped to respect max.
+func (l *errList) errs() []error {
+	if l.tail == nil {
+		return l.head
+	}
+
+	omitted := l.total - len(l.head) - len(l.tail)
+	out := make([]error, 0, len(l.head)+len(l.tail)+1)
+	out = append(out, l.head...)
+	if omitted > 0 {
+		out = append(out, fmt.Errorf("... %d omitted ...", omitted))
+	}
+	for i := 0; i < len(l.tail); i++ {
+		out = append(out, l.tail[(l.pos+i)%len(l.tail)])
+	}
+
+	return out
+}
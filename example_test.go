@@ -42,3 +42,30 @@ func ExampleTryer() {
 	// Output:
 	// Succeeded on try #2
 }
+
+func ExampleDo() {
+	tr := retry.Tryer{
+		Max:   5,
+		Delay: 100 * time.Millisecond,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// Retry a function that produces a value, failing on its first two tries.
+	result, err := retry.Do(ctx, tr, func(n int) (string, error) {
+		if n < 2 {
+			return "", fmt.Errorf("failed on try #%d", n)
+		}
+		return fmt.Sprintf("result from try #%d", n), nil
+	})
+
+	if err != nil {
+		fmt.Printf("Error: %s\n", err)
+		return
+	}
+	fmt.Println(result)
+
+	// Output:
+	// result from try #2
+}
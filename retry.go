@@ -17,9 +17,9 @@ import (
 // The interval can optionally scale up after each attempt,
 // for exponential backoff.
 //
-// There is no MaxTime field.
 // To limit the total time spent retrying,
-// set a deadline on the context passed to [Tryer.Try].
+// either set a deadline on the context passed to [Tryer.Try]
+// or set the MaxElapsed field.
 type Tryer struct {
 	// Max is the maximum number of tries to make.
 	// [Tryer.Try] always makes at least one attempt.
@@ -60,8 +60,101 @@ type Tryer struct {
 	// Rand is an optional function that returns a random float64 in the range [0, 1).
 	// If it is nil, [rand.Float64] is used.
 	Rand func() float64
+
+	// RetryAfter is an optional function that inspects the error returned by f
+	// and reports a server-requested delay to use instead of the computed backoff
+	// (e.g., from an HTTP 429's Retry-After header, or a gRPC ResourceExhausted status).
+	// It returns the requested duration and true if one applies to err,
+	// or false if the computed backoff should be used unchanged.
+	// The duration it returns is combined with the computed delay according to RetryAfterPolicy,
+	// and is still subject to MaxDelay and context cancellation.
+	RetryAfter func(error) (time.Duration, bool)
+
+	// RetryAfterPolicy determines how the duration from RetryAfter is combined
+	// with the delay [Tryer.Try] would otherwise use.
+	// It has no effect unless RetryAfter is set.
+	// The zero value, RetryAfterMax, never lets RetryAfter shorten the computed backoff.
+	RetryAfterPolicy RetryAfterPolicy
+
+	// Strategy selects the backoff algorithm used to compute the delay between attempts.
+	// The zero value, StrategyExponential, uses Delay, Scale, and Jitter as described above.
+	Strategy Strategy
+
+	// OnRetry is an optional function invoked after f fails but before [Tryer.Try] waits to retry it.
+	// It receives the number of attempts made so far (1 after the first attempt, numbered 0, fails),
+	// the error f returned, and the delay about to be used.
+	// If it returns retry == false, Try stops immediately and returns an [AbortedError] wrapping override
+	// (or, if override is nil, the error from f).
+	// If override is non-nil, it replaces the error from f for the remainder of Try,
+	// including in whatever terminal error Try eventually returns.
+	//
+	// OnRetry is a hook for conditions richer than IsRetryable can express,
+	// such as circuit-breaking, metrics, or logging.
+	OnRetry func(attempt int, err error, delay time.Duration) (retry bool, override error)
+
+	// MaxErrors caps the number of attempt errors retained in [MaxTriesError], [ContextError], and [TimeoutError]'s error lists.
+	// 0 (the default) retains all of them.
+	// A positive value retains roughly the first and last MaxErrors/2 of them,
+	// with a placeholder error noting how many were dropped in between.
+	MaxErrors int
+
+	// MaxElapsed caps the total time [Tryer.Try] spends across all attempts, independent of ctx.
+	// This is useful when callers want a retry-only budget
+	// while still passing a longer-lived context (e.g. one scoped to an entire request).
+	// Unlike a context deadline, MaxElapsed is checked only between attempts,
+	// so it never interrupts an attempt already in progress; see MaxAttemptTime for that.
+	// 0 (the default) means no limit; Try relies solely on ctx and Max in that case.
+	// When the budget is exhausted, Try returns a [TimeoutError].
+	MaxElapsed time.Duration
+
+	// MaxAttemptTime caps the time a single call to f is given to complete.
+	// If it elapses before f returns, Try treats the attempt as having failed
+	// with ctx's deadline-exceeded error, and proceeds to the next attempt (or gives up) as usual.
+	// Because f has no way to observe this deadline itself,
+	// the abandoned call to f keeps running in the background until it eventually returns,
+	// racing against whatever the next attempt (or the caller, once Try has returned) does with
+	// any state f closes over. Only set MaxAttemptTime for an f that is safe to keep running
+	// unsupervised, or that synchronizes its own access to such state.
+	// 0 (the default) means attempts are not individually time-limited.
+	MaxAttemptTime time.Duration
 }
 
+// Strategy selects the backoff algorithm [Tryer.Try] uses to compute the delay between attempts.
+type Strategy int
+
+const (
+	// StrategyExponential computes the delay from Delay, Scale, and Jitter. This is the default (zero) strategy.
+	StrategyExponential Strategy = iota
+
+	// StrategyDecorrelatedJitter implements AWS's "decorrelated jitter" algorithm,
+	// which helps avoid the thundering-herd effect that exponential backoff with symmetric jitter
+	// can produce under high concurrency.
+	// It computes sleep_n = min(MaxDelay, random_between(Delay, sleep_{n-1} * 3)),
+	// seeding sleep_0 with Delay.
+	// Scale and Jitter are ignored.
+	StrategyDecorrelatedJitter
+
+	// StrategyFullJitter computes sleep_n = random_between(0, min(MaxDelay, Delay * 2^n)).
+	// Scale and Jitter are ignored.
+	StrategyFullJitter
+)
+
+// RetryAfterPolicy determines how a duration from [Tryer.RetryAfter]
+// is combined with the delay otherwise computed for the next attempt.
+type RetryAfterPolicy int
+
+const (
+	// RetryAfterMax uses whichever of the computed delay and the RetryAfter duration is longer.
+	// This is the default (zero) policy.
+	RetryAfterMax RetryAfterPolicy = iota
+
+	// RetryAfterReplace discards the computed delay and uses the RetryAfter duration instead.
+	RetryAfterReplace
+
+	// RetryAfterAdd adds the RetryAfter duration to the computed delay.
+	RetryAfterAdd
+)
+
 // Try runs the provided function one or more times until it succeeds,
 // or the provided context is canceled,
 // or certain other conditions are met - see [Tryer].
@@ -71,37 +164,128 @@ type Tryer struct {
 //
 // If f succeeds (i.e., returns nil), Try returns nil.
 // Otherwise it returns one of these error-wrapper types:
-// [UnretryableError], [MaxTriesError], or [ContextError].
+// [UnretryableError], [MaxTriesError], [AbortedError], [ContextError], or [TimeoutError].
 func (tr Tryer) Try(ctx context.Context, f func(int) error) error {
-	n := 0
+	var (
+		n         = 0
+		lastDelay time.Duration
+		errs      errList
+		start     = time.Now()
+	)
+	errs.max = tr.MaxErrors
 
 	for {
-		err := f(n)
+		err := tr.call(ctx, n, f)
 		if err == nil {
 			return nil
 		}
 
 		n++
+
+		if n == 1 {
+			lastDelay = tr.Delay
+		}
+
+		delay := tr.calcDelay(n, lastDelay)
+		lastDelay = delay
+
+		if tr.RetryAfter != nil {
+			if d, ok := tr.RetryAfter(err); ok {
+				delay = tr.applyRetryAfter(delay, d)
+			}
+		}
+
+		var elapsedExceeded bool
+		if tr.MaxElapsed > 0 {
+			remaining := tr.MaxElapsed - time.Since(start)
+			if remaining <= 0 {
+				elapsedExceeded = true
+			} else if delay > remaining {
+				delay = remaining
+			}
+		}
+
+		// OnRetry runs before the terminal-error decisions below, unconditionally -
+		// including when this attempt was cut short by ctx cancellation - so that
+		// callers using it for metrics/logging/circuit-breaking never lose visibility
+		// into an attempt, and so an override it supplies replaces err in whichever
+		// terminal error (MaxTriesError, UnretryableError, TimeoutError, or ContextError)
+		// Try goes on to return.
+		if tr.OnRetry != nil {
+			retry, override := tr.OnRetry(n, err, delay)
+			if override != nil {
+				err = override
+			}
+			if !retry {
+				return AbortedError{Err: err}
+			}
+		}
+		errs.add(err)
+
+		// If call timed out because ctx itself was canceled rather than MaxAttemptTime
+		// elapsing, report that as cancellation rather than as an ordinary attempt error,
+		// skipping the Max/IsRetryable/MaxElapsed classification below as moot.
+		if tr.MaxAttemptTime > 0 && ctx.Err() != nil {
+			return ContextError{Err: ctx.Err(), Errs: errs.errs()}
+		}
+
 		if tr.Max >= 0 && n >= tr.Max {
-			return MaxTriesError{Err: err}
+			return MaxTriesError{Err: err, Errs: errs.errs()}
 		}
 
 		if tr.IsRetryable != nil && !tr.IsRetryable(err) {
 			return UnretryableError{Err: err}
 		}
 
-		delay := tr.calcDelay(n)
+		if elapsedExceeded {
+			return TimeoutError{Err: err, Errs: errs.errs()}
+		}
 
 		select {
 		case <-ctx.Done():
-			return ContextError{Err: ctx.Err()}
+			return ContextError{Err: ctx.Err(), Errs: errs.errs()}
 		case <-tr.after(delay):
 		}
 	}
 }
 
-// Computes a delay before try number n.
-func (tr Tryer) calcDelay(n int) time.Duration {
+// call invokes f(n), bounding it by tr.MaxAttemptTime when set.
+// If f does not return before the deadline, call returns ctx's deadline-exceeded error
+// and leaves f running in the background, since f has no way to observe the deadline itself.
+func (tr Tryer) call(ctx context.Context, n int, f func(int) error) error {
+	if tr.MaxAttemptTime <= 0 {
+		return f(n)
+	}
+
+	attemptCtx, cancel := context.WithTimeout(ctx, tr.MaxAttemptTime)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- f(n) }()
+
+	select {
+	case err := <-done:
+		return err
+	case <-attemptCtx.Done():
+		return attemptCtx.Err()
+	}
+}
+
+// Computes a delay before try number n, given the delay used (or seeded) before try number n-1.
+func (tr Tryer) calcDelay(n int, lastDelay time.Duration) time.Duration {
+	switch tr.Strategy {
+	case StrategyDecorrelatedJitter:
+		return tr.decorrelatedJitterDelay(lastDelay)
+	case StrategyFullJitter:
+		return tr.fullJitterDelay(n)
+	default:
+		return tr.exponentialDelay(n)
+	}
+}
+
+// exponentialDelay computes the delay before try number n
+// using the Delay, Scale, and Jitter fields.
+func (tr Tryer) exponentialDelay(n int) time.Duration {
 	delay := tr.Delay
 	if tr.Scale > 0 {
 		scale := math.Pow(1+tr.Scale, float64(n-1))
@@ -126,6 +310,57 @@ func (tr Tryer) calcDelay(n int) time.Duration {
 	return delay
 }
 
+// decorrelatedJitterDelay implements AWS's "decorrelated jitter" algorithm:
+// min(MaxDelay, random_between(Delay, lastDelay*3)).
+func (tr Tryer) decorrelatedJitterDelay(lastDelay time.Duration) time.Duration {
+	lo := float64(tr.Delay)
+	hi := float64(lastDelay) * 3
+	if hi < lo {
+		hi = lo
+	}
+
+	delay := time.Duration(lo + tr.randFloat()*(hi-lo))
+	if tr.MaxDelay > 0 && delay > tr.MaxDelay {
+		delay = tr.MaxDelay
+	}
+
+	return delay
+}
+
+// fullJitterDelay computes random_between(0, min(MaxDelay, Delay * 2^n)).
+func (tr Tryer) fullJitterDelay(n int) time.Duration {
+	maxD := time.Duration(float64(tr.Delay) * math.Pow(2, float64(n)))
+	if tr.MaxDelay > 0 && maxD > tr.MaxDelay {
+		maxD = tr.MaxDelay
+	}
+	if maxD < 0 {
+		maxD = 0
+	}
+
+	return time.Duration(tr.randFloat() * float64(maxD))
+}
+
+// applyRetryAfter combines a server-requested delay d with the computed delay,
+// according to tr.RetryAfterPolicy, and re-applies the MaxDelay cap.
+func (tr Tryer) applyRetryAfter(delay, d time.Duration) time.Duration {
+	switch tr.RetryAfterPolicy {
+	case RetryAfterReplace:
+		delay = d
+	case RetryAfterAdd:
+		delay += d
+	default: // RetryAfterMax
+		if d > delay {
+			delay = d
+		}
+	}
+
+	if tr.MaxDelay > 0 && delay > tr.MaxDelay {
+		delay = tr.MaxDelay
+	}
+
+	return delay
+}
+
 func (tr Tryer) randFloat() float64 {
 	f := tr.Rand
 	if f == nil {
@@ -160,25 +395,77 @@ func (e UnretryableError) Unwrap() error {
 // wrapping the error returned by the function
 // after the maximum number of tries is reached.
 type MaxTriesError struct {
+	// Err is the error returned by the final attempt.
 	Err error
+
+	// Errs holds every non-nil error returned by the function across all attempts, in order.
+	// It is subject to capping by [Tryer.MaxErrors]; Err is always its last element, unless capped.
+	Errs []error
 }
 
 func (e MaxTriesError) Error() string {
 	return "reached maximum retries: " + e.Err.Error()
 }
-func (e MaxTriesError) Unwrap() error {
+
+// Unwrap returns every error accumulated in e.Errs, so that [errors.Is] and [errors.As]
+// can find a match among any of them, not just the final one in e.Err.
+func (e MaxTriesError) Unwrap() []error {
+	return e.Errs
+}
+
+// AbortedError is an error returned by [Tryer.Try]
+// wrapping the error returned by the function
+// when [Tryer.OnRetry] requests that retrying stop.
+type AbortedError struct {
+	Err error
+}
+
+func (e AbortedError) Error() string {
+	return "aborted: " + e.Err.Error()
+}
+func (e AbortedError) Unwrap() error {
 	return e.Err
 }
 
 // ContextError is an error returned by [Tryer.Try]
 // wrapping the context error when the context is canceled.
 type ContextError struct {
+	// Err is the error from the context, i.e. ctx.Err().
 	Err error
+
+	// Errs holds every non-nil error returned by the function across all attempts, in order.
+	// It is subject to capping by [Tryer.MaxErrors].
+	Errs []error
 }
 
 func (e ContextError) Error() string {
 	return "context error: " + e.Err.Error()
 }
-func (e ContextError) Unwrap() error {
-	return e.Err
+
+// Unwrap returns e.Err together with every error accumulated in e.Errs,
+// so that [errors.Is] and [errors.As] can find a match among any of them.
+func (e ContextError) Unwrap() []error {
+	return append(append([]error{}, e.Errs...), e.Err)
+}
+
+// TimeoutError is an error returned by [Tryer.Try]
+// wrapping the error returned by the most recent attempt
+// when [Tryer.MaxElapsed] elapses before the next attempt can be made.
+type TimeoutError struct {
+	// Err is the error returned by the most recent attempt.
+	Err error
+
+	// Errs holds every non-nil error returned by the function across all attempts, in order.
+	// It is subject to capping by [Tryer.MaxErrors]; Err is always its last element, unless capped.
+	Errs []error
+}
+
+func (e TimeoutError) Error() string {
+	return "exceeded MaxElapsed: " + e.Err.Error()
+}
+
+// Unwrap returns every error accumulated in e.Errs, so that [errors.Is] and [errors.As]
+// can find a match among any of them, not just the final one in e.Err.
+func (e TimeoutError) Unwrap() []error {
+	return e.Errs
 }
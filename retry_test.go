@@ -166,6 +166,92 @@ func TestCancel(t *testing.T) {
 	}
 }
 
+func TestRetryAfter(t *testing.T) {
+	testErr := fmt.Errorf("test error")
+
+	cases := []struct {
+		name   string
+		policy RetryAfterPolicy
+		want   time.Duration
+	}{
+		{"replace", RetryAfterReplace, 500 * time.Millisecond},
+		{"max", RetryAfterMax, 500 * time.Millisecond},
+		{"add", RetryAfterAdd, 600 * time.Millisecond},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var got time.Duration
+
+			tr := Tryer{
+				Max:              -1,
+				Delay:            100 * time.Millisecond,
+				RetryAfterPolicy: c.policy,
+				RetryAfter: func(err error) (time.Duration, bool) {
+					if !errors.Is(err, testErr) {
+						return 0, false
+					}
+					return 500 * time.Millisecond, true
+				},
+				After: func(d time.Duration) <-chan time.Time {
+					got = d
+					ch := make(chan time.Time, 1)
+					ch <- time.Now()
+					return ch
+				},
+			}
+
+			err := tr.Try(context.Background(), func(n int) error {
+				if n == 0 {
+					return testErr
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("got error %v, want nil", err)
+			}
+			if got != c.want {
+				t.Errorf("got delay %v, want %v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestRetryAfterDefaultPolicy(t *testing.T) {
+	testErr := fmt.Errorf("test error")
+
+	var got time.Duration
+
+	// RetryAfterPolicy is left unset, so the zero value (RetryAfterMax) applies:
+	// RetryAfter's 500ms should win over the shorter 100ms computed delay.
+	tr := Tryer{
+		Max:   -1,
+		Delay: 100 * time.Millisecond,
+		RetryAfter: func(err error) (time.Duration, bool) {
+			return 500 * time.Millisecond, true
+		},
+		After: func(d time.Duration) <-chan time.Time {
+			got = d
+			ch := make(chan time.Time, 1)
+			ch <- time.Now()
+			return ch
+		},
+	}
+
+	err := tr.Try(context.Background(), func(n int) error {
+		if n == 0 {
+			return testErr
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	if want := 500 * time.Millisecond; got != want {
+		t.Errorf("got delay %v, want %v", got, want)
+	}
+}
+
 func TestCalcDelay(t *testing.T) {
 	floats := []float64{0.1, 0.9, 0.2}
 
@@ -187,8 +273,369 @@ func TestCalcDelay(t *testing.T) {
 	}
 
 	for i := 0; i < 3; i++ {
-		if got := tr.calcDelay(i + 1); got != want[i] {
+		if got := tr.calcDelay(i+1, 0); got != want[i] {
 			t.Errorf("calcDelay(%d) = %v, want %v", i, got, want[i])
 		}
 	}
 }
+
+func TestOnRetry(t *testing.T) {
+	testErr := fmt.Errorf("test error")
+	overrideErr := fmt.Errorf("override error")
+
+	t.Run("abort", func(t *testing.T) {
+		var calls int
+
+		tr := Tryer{
+			Max: -1,
+			OnRetry: func(attempt int, err error, delay time.Duration) (bool, error) {
+				calls++
+				return false, overrideErr
+			},
+		}
+
+		err := tr.Try(context.Background(), func(int) error {
+			return testErr
+		})
+
+		if calls != 1 {
+			t.Errorf("got %d calls to OnRetry, want 1", calls)
+		}
+
+		var aborted AbortedError
+		if !errors.As(err, &aborted) {
+			t.Fatalf("got %T, want AbortedError", err)
+		}
+		if !errors.Is(err, overrideErr) {
+			t.Errorf("got %v, want %v", err, overrideErr)
+		}
+	})
+
+	t.Run("continue", func(t *testing.T) {
+		var attempts []int
+
+		tr := Tryer{
+			Max: 3,
+			After: func(time.Duration) <-chan time.Time {
+				ch := make(chan time.Time, 1)
+				ch <- time.Now()
+				return ch
+			},
+			OnRetry: func(attempt int, err error, delay time.Duration) (bool, error) {
+				attempts = append(attempts, attempt)
+				return true, nil
+			},
+		}
+
+		err := tr.Try(context.Background(), func(int) error {
+			return testErr
+		})
+
+		var maxErr MaxTriesError
+		if !errors.As(err, &maxErr) {
+			t.Fatalf("got %T, want MaxTriesError", err)
+		}
+
+		// OnRetry runs on every failed attempt, including the one that exhausts Max,
+		// so it sees attempt 3 too.
+		want := []int{1, 2, 3}
+		if len(attempts) != len(want) {
+			t.Fatalf("got attempts %v, want %v", attempts, want)
+		}
+		for i := range want {
+			if attempts[i] != want[i] {
+				t.Errorf("got attempts %v, want %v", attempts, want)
+				break
+			}
+		}
+	})
+
+	t.Run("override reaches terminal error", func(t *testing.T) {
+		var calls int
+
+		tr := Tryer{
+			Max: -1,
+			IsRetryable: func(error) bool {
+				return false
+			},
+			OnRetry: func(attempt int, err error, delay time.Duration) (bool, error) {
+				calls++
+				return true, overrideErr
+			},
+		}
+
+		err := tr.Try(context.Background(), func(int) error {
+			return testErr
+		})
+
+		if calls != 1 {
+			t.Errorf("got %d calls to OnRetry, want 1", calls)
+		}
+
+		var unretryable UnretryableError
+		if !errors.As(err, &unretryable) {
+			t.Fatalf("got %T, want UnretryableError", err)
+		}
+		if !errors.Is(err, overrideErr) {
+			t.Errorf("got %v, want an error wrapping %v", err, overrideErr)
+		}
+		if errors.Is(err, testErr) {
+			t.Errorf("got %v, want override to have replaced testErr", err)
+		}
+	})
+}
+
+func TestMaxErrors(t *testing.T) {
+	t.Run("uncapped", func(t *testing.T) {
+		tr := Tryer{Max: 4}
+
+		var n int
+		err := tr.Try(context.Background(), func(i int) error {
+			n = i
+			return fmt.Errorf("error %d", i)
+		})
+
+		var maxErr MaxTriesError
+		if !errors.As(err, &maxErr) {
+			t.Fatalf("got %T, want MaxTriesError", err)
+		}
+		if len(maxErr.Errs) != 4 {
+			t.Fatalf("got %d errors, want 4", len(maxErr.Errs))
+		}
+		if maxErr.Errs[n] != maxErr.Err {
+			t.Errorf("got last error %v, want %v", maxErr.Errs[n], maxErr.Err)
+		}
+		for i, e := range maxErr.Errs {
+			want := fmt.Sprintf("error %d", i)
+			if e.Error() != want {
+				t.Errorf("got Errs[%d] == %q, want %q", i, e.Error(), want)
+			}
+		}
+	})
+
+	t.Run("capped", func(t *testing.T) {
+		tr := Tryer{Max: 6, MaxErrors: 4}
+
+		err := tr.Try(context.Background(), func(i int) error {
+			return fmt.Errorf("error %d", i)
+		})
+
+		var maxErr MaxTriesError
+		if !errors.As(err, &maxErr) {
+			t.Fatalf("got %T, want MaxTriesError", err)
+		}
+		// 2 head errors, 1 placeholder, 2 tail errors (but capped to 4 total slots: 2 head + 2 tail).
+		if len(maxErr.Errs) != 5 {
+			t.Fatalf("got %d errors, want 5 (including placeholder)", len(maxErr.Errs))
+		}
+		if maxErr.Errs[0].Error() != "error 0" || maxErr.Errs[1].Error() != "error 1" {
+			t.Errorf("got head errors %v, %v, want error 0, error 1", maxErr.Errs[0], maxErr.Errs[1])
+		}
+		if maxErr.Errs[len(maxErr.Errs)-1] != maxErr.Err {
+			t.Errorf("got last error %v, want %v", maxErr.Errs[len(maxErr.Errs)-1], maxErr.Err)
+		}
+	})
+
+	t.Run("capped at one", func(t *testing.T) {
+		tr := Tryer{Max: 5, MaxErrors: 1}
+
+		err := tr.Try(context.Background(), func(i int) error {
+			return fmt.Errorf("error %d", i)
+		})
+
+		var maxErr MaxTriesError
+		if !errors.As(err, &maxErr) {
+			t.Fatalf("got %T, want MaxTriesError", err)
+		}
+		// A single retained error is still a drop; it must be marked, not silent.
+		if len(maxErr.Errs) != 2 {
+			t.Fatalf("got %d errors, want 2 (a placeholder and the last error)", len(maxErr.Errs))
+		}
+		if maxErr.Errs[1] != maxErr.Err {
+			t.Errorf("got last error %v, want %v", maxErr.Errs[1], maxErr.Err)
+		}
+	})
+}
+
+func TestMaxElapsed(t *testing.T) {
+	testErr := fmt.Errorf("test error")
+
+	tr := Tryer{
+		Max:        -1,
+		Delay:      time.Second,
+		MaxElapsed: 250 * time.Millisecond,
+		After: func(d time.Duration) <-chan time.Time {
+			ch := make(chan time.Time, 1)
+			ch <- time.Now()
+			return ch
+		},
+	}
+
+	var n int
+	err := tr.Try(context.Background(), func(i int) error {
+		n = i
+		return testErr
+	})
+
+	var timeoutErr TimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("got %T, want TimeoutError", err)
+	}
+	if !errors.Is(err, testErr) {
+		t.Errorf("got %v, want %v", err, testErr)
+	}
+	if n == 0 {
+		t.Errorf("got n==0, want at least one retry before the budget was exhausted")
+	}
+}
+
+func TestMaxAttemptTimeCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	tr := Tryer{
+		Max:            -1,
+		MaxAttemptTime: time.Hour, // long enough that only ctx cancellation can trigger it
+	}
+
+	hang := make(chan struct{})
+	defer close(hang)
+
+	started := make(chan struct{})
+
+	done := make(chan error, 1)
+	go func() {
+		done <- tr.Try(ctx, func(i int) error {
+			close(started)
+			<-hang
+			return nil
+		})
+	}()
+
+	<-started
+	cancel()
+
+	err := <-done
+
+	var ctxErr ContextError
+	if !errors.As(err, &ctxErr) {
+		t.Fatalf("got %T, want ContextError", err)
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("got %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestMaxAttemptTimeCancelInvokesOnRetry(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var calls int
+
+	tr := Tryer{
+		Max:            -1,
+		MaxAttemptTime: time.Hour, // long enough that only ctx cancellation can trigger it
+		OnRetry: func(attempt int, err error, delay time.Duration) (bool, error) {
+			calls++
+			return true, nil
+		},
+	}
+
+	hang := make(chan struct{})
+	defer close(hang)
+
+	started := make(chan struct{})
+
+	done := make(chan error, 1)
+	go func() {
+		done <- tr.Try(ctx, func(i int) error {
+			close(started)
+			<-hang
+			return nil
+		})
+	}()
+
+	<-started
+	cancel()
+
+	err := <-done
+
+	if calls != 1 {
+		t.Errorf("got %d calls to OnRetry, want 1 - it must run even when ctx cancellation cuts the attempt short", calls)
+	}
+
+	var ctxErr ContextError
+	if !errors.As(err, &ctxErr) {
+		t.Fatalf("got %T, want ContextError", err)
+	}
+}
+
+func TestMaxAttemptTime(t *testing.T) {
+	tr := Tryer{
+		Max:            2,
+		MaxAttemptTime: 10 * time.Millisecond,
+		After: func(time.Duration) <-chan time.Time {
+			ch := make(chan time.Time, 1)
+			ch <- time.Now()
+			return ch
+		},
+	}
+
+	hang := make(chan struct{})
+	defer close(hang)
+
+	err := tr.Try(context.Background(), func(i int) error {
+		<-hang // never returns within MaxAttemptTime
+		return nil
+	})
+
+	var maxErr MaxTriesError
+	if !errors.As(err, &maxErr) {
+		t.Fatalf("got %T, want MaxTriesError", err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got %v, want an error wrapping context.DeadlineExceeded", err)
+	}
+}
+
+func TestCalcDelayStrategies(t *testing.T) {
+	t.Run("decorrelated jitter", func(t *testing.T) {
+		tr := Tryer{
+			Strategy: StrategyDecorrelatedJitter,
+			Delay:    100 * time.Millisecond,
+			MaxDelay: 1 * time.Second,
+			Rand:     func() float64 { return 1 }, // pick the top of the range
+		}
+
+		// First retry: lastDelay is seeded with Delay, so the range is [100ms, 300ms).
+		if got, want := tr.calcDelay(1, tr.Delay), 300*time.Millisecond; got != want {
+			t.Errorf("got %v, want %v", got, want)
+		}
+
+		// Second retry: lastDelay is the delay just computed, so the range is [100ms, 900ms).
+		if got, want := tr.calcDelay(2, 300*time.Millisecond), 900*time.Millisecond; got != want {
+			t.Errorf("got %v, want %v", got, want)
+		}
+
+		// MaxDelay caps the result.
+		if got, want := tr.calcDelay(3, 900*time.Millisecond), tr.MaxDelay; got != want {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("full jitter", func(t *testing.T) {
+		tr := Tryer{
+			Strategy: StrategyFullJitter,
+			Delay:    100 * time.Millisecond,
+			MaxDelay: 300 * time.Millisecond,
+			Rand:     func() float64 { return 1 }, // pick the top of the range
+		}
+
+		if got, want := tr.calcDelay(1, 0), 200*time.Millisecond; got != want {
+			t.Errorf("got %v, want %v", got, want)
+		}
+
+		// MaxDelay caps the range before the random factor is applied.
+		if got, want := tr.calcDelay(2, 0), tr.MaxDelay; got != want {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+}
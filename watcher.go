@@ -0,0 +1,119 @@
+package retry
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// Watch names a channel to watch and a function to run,
+// via the enclosing [Watcher]'s Tryer policy,
+// whenever a value arrives on that channel.
+type Watch struct {
+	// Name identifies the watch, for diagnostic or logging purposes.
+	// It is not otherwise used by [Watcher].
+	Name string
+
+	// Ch is the channel to watch. Each value received triggers an invocation of Fn.
+	Ch <-chan any
+
+	// Fn is invoked, under the enclosing Watcher's Tryer policy, whenever a value arrives on Ch.
+	Fn func(context.Context) error
+}
+
+// Watcher runs a collection of independently retried [Watch] handlers for as long as a context remains active.
+// It is meant for long-running loops that must react to several asynchronous triggers
+// (config reload, cache invalidation, upstream reconnect),
+// each handled with the same retry policy,
+// where a failure (and ensuing retries) in one must not affect the others.
+type Watcher struct {
+	Tryer
+
+	// Watches are the channels to watch and the functions to run when they fire.
+	Watches []Watch
+}
+
+// Run blocks, watching ctx.Done() and every channel in w.Watches.
+//
+// Whenever a Watch's channel receives a value,
+// its Fn is run via w.Tryer.Try in its own goroutine,
+// so a failing (or slow-to-retry) watch cannot block or affect the others.
+// At most one invocation of a given Watch's Fn (including its retries) runs at a time;
+// if the Watch's channel fires again while one is still in flight,
+// the new trigger is dropped rather than queued or run concurrently.
+//
+// Run returns ctx.Err() once ctx is done,
+// after waiting for any watches still in flight to finish.
+func (w Watcher) Run(ctx context.Context) error {
+	var (
+		wg   sync.WaitGroup
+		busy = make([]atomic.Bool, len(w.Watches))
+	)
+	defer wg.Wait()
+
+	cases := make([]reflect.SelectCase, len(w.Watches)+1)
+	cases[0] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())}
+	for i, watch := range w.Watches {
+		cases[i+1] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(watch.Ch)}
+	}
+
+	for {
+		chosen, _, ok := reflect.Select(cases)
+		if chosen == 0 {
+			return ctx.Err()
+		}
+		if !ok {
+			// The channel is closed; stop selecting on it.
+			cases[chosen] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf((chan any)(nil))}
+			continue
+		}
+
+		i := chosen - 1
+		if !busy[i].CompareAndSwap(false, true) {
+			// A previous invocation of this watch is still in flight; drop the trigger.
+			continue
+		}
+
+		watch := w.Watches[i]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer busy[i].Store(false)
+			w.Tryer.Try(ctx, func(int) error {
+				return watch.Fn(ctx)
+			})
+		}()
+	}
+}
+
+// WithWatch builds a [Watch] named name from a typed channel ch and a handler fn,
+// hiding the reflect boilerplate [Watcher] needs to watch channels of arbitrary element type.
+//
+// It starts a goroutine that forwards every value received from ch until ch is closed
+// or ctx is done, at which point the Watch's channel is closed too.
+// ctx should be the same context that will be passed to [Watcher.Run];
+// otherwise the forwarding goroutine has no way to know [Watcher.Run] has stopped draining it,
+// and a send on ch after Run returns blocks it forever.
+func WithWatch[T any](ctx context.Context, name string, ch <-chan T, fn func(context.Context) error) Watch {
+	anyCh := make(chan any)
+	go func() {
+		defer close(anyCh)
+		for {
+			select {
+			case v, ok := <-ch:
+				if !ok {
+					return
+				}
+				select {
+				case anyCh <- v:
+				case <-ctx.Done():
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return Watch{Name: name, Ch: anyCh, Fn: fn}
+}
@@ -0,0 +1,160 @@
+package retry
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWatcher(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var (
+		configCalls int32
+		cacheCalls  int32
+	)
+
+	configCh := make(chan int)
+	cacheCh := make(chan string)
+
+	w := Watcher{
+		Tryer: Tryer{Max: 1},
+		Watches: []Watch{
+			WithWatch(ctx, "config", configCh, func(context.Context) error {
+				atomic.AddInt32(&configCalls, 1)
+				return nil
+			}),
+			WithWatch(ctx, "cache", cacheCh, func(context.Context) error {
+				atomic.AddInt32(&cacheCalls, 1)
+				return nil
+			}),
+		},
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- w.Run(ctx)
+	}()
+
+	configCh <- 1
+	cacheCh <- "invalidate"
+	configCh <- 2
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if atomic.LoadInt32(&configCalls) == 2 && atomic.LoadInt32(&cacheCalls) == 1 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if got := atomic.LoadInt32(&configCalls); got != 2 {
+		t.Errorf("got %d config calls, want 2", got)
+	}
+	if got := atomic.LoadInt32(&cacheCalls); got != 1 {
+		t.Errorf("got %d cache calls, want 1", got)
+	}
+
+	cancel()
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Error("got nil error from Run, want context.Canceled")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return after cancel")
+	}
+}
+
+// TestWithWatchStopsForwardingAfterCancel checks that the goroutine WithWatch starts
+// to forward values onto its internal channel exits once ctx is done,
+// rather than leaking forever waiting on a send nobody is left to receive.
+func TestWithWatchStopsForwardingAfterCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	ch := make(chan int)
+	watch := WithWatch(ctx, "test", ch, func(context.Context) error {
+		return nil
+	})
+
+	cancel()
+
+	select {
+	case _, ok := <-watch.Ch:
+		if ok {
+			t.Error("got a forwarded value after ctx was canceled, want the channel closed")
+		}
+	case <-time.After(500 * time.Millisecond):
+		t.Fatal("Watch.Ch was not closed after ctx was canceled")
+	}
+
+	// Now that the forwarding goroutine has exited, nothing receives from the
+	// source channel, so a send on it must not be accepted.
+	select {
+	case ch <- 1:
+		t.Error("got a send accepted on ch, want no goroutine left to receive it")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+// TestWatcherDropsOverlappingInvocations checks that a Watch's channel firing
+// again while its Fn (and retries) are still running does not spawn a second,
+// concurrent invocation: the new trigger is dropped instead.
+func TestWatcherDropsOverlappingInvocations(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var (
+		calls   int32
+		started = make(chan struct{}, 1)
+		release = make(chan struct{})
+	)
+
+	ch := make(chan int)
+	w := Watcher{
+		Tryer: Tryer{Max: 1},
+		Watches: []Watch{
+			WithWatch(ctx, "slow", ch, func(context.Context) error {
+				atomic.AddInt32(&calls, 1)
+				select {
+				case started <- struct{}{}:
+				default:
+				}
+				<-release
+				return nil
+			}),
+		},
+	}
+
+	go w.Run(ctx)
+
+	ch <- 1
+	<-started // the first invocation is now in flight, blocked on release
+
+	for i := 0; i < 5; i++ {
+		ch <- i // fired while the watch is busy; each must be dropped, not queued
+	}
+
+	time.Sleep(50 * time.Millisecond) // give any (wrongly) spawned extra goroutines a chance to start
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Fatalf("got %d calls while the first invocation was still in flight, want 1", got)
+	}
+
+	close(release)
+
+	// Keep retrying the send: the first trigger to land after busy clears may lose
+	// a race with the still-unwinding previous goroutine and get dropped too.
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) && atomic.LoadInt32(&calls) < 2 {
+		select {
+		case ch <- 99:
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	if got := atomic.LoadInt32(&calls); got < 2 {
+		t.Errorf("got %d calls, want at least 2 - the watch should accept new triggers once free", got)
+	}
+}